@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// BackupRecord is one DNS record captured before deletion, with enough
+// information for `restore` to re-create it exactly.
+type BackupRecord struct {
+	ZoneID   string `json:"zone_id"`
+	Hostname string `json:"hostname"`
+	Type     string `json:"type"`
+	Content  string `json:"content"`
+	TTL      int    `json:"ttl"`
+	Priority uint16 `json:"priority"`
+	Proxied  bool   `json:"proxied"`
+}
+
+// BackupFile is the on-disk shape of a zone's backup, written under
+// --backup-dir before its records are deleted.
+type BackupFile struct {
+	Provider  string         `json:"provider"`
+	ZoneName  string         `json:"zone_name"`
+	CreatedAt time.Time      `json:"created_at"`
+	Records   []BackupRecord `json:"records"`
+}
+
+// backupWriter accumulates deleted records grouped by zone and rewrites that
+// zone's backup file to disk on every add, so a record is durable as soon as
+// it's about to be deleted rather than only once the whole run finishes. It
+// is safe for concurrent use by the worker pool.
+type backupWriter struct {
+	mu       sync.Mutex
+	dir      string
+	provider string
+	files    map[string]*BackupFile
+}
+
+func newBackupWriter(dir, provider string) *backupWriter {
+	return &backupWriter{dir: dir, provider: provider, files: make(map[string]*BackupFile)}
+}
+
+// add records rec as about to be deleted from zoneName and immediately
+// rewrites that zone's backup file so the record survives a crash partway
+// through the run.
+func (b *backupWriter) add(zoneName string, rec BackupRecord) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	file, ok := b.files[zoneName]
+	if !ok {
+		file = &BackupFile{Provider: b.provider, ZoneName: zoneName, CreatedAt: time.Now()}
+		b.files[zoneName] = file
+	}
+	file.Records = append(file.Records, rec)
+
+	if err := os.MkdirAll(b.dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(b.dir, backupFilename(zoneName, file.CreatedAt))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	logger.Info("Wrote backup", zap.String("zone", zoneName), zap.String("file", path), zap.Int("records", len(file.Records)))
+	return nil
+}
+
+var unsafeFilenameChars = regexp.MustCompile(`[^a-zA-Z0-9.-]+`)
+
+func backupFilename(zoneName string, createdAt time.Time) string {
+	safeZone := unsafeFilenameChars.ReplaceAllString(zoneName, "_")
+	return fmt.Sprintf("%s-%s.json", safeZone, createdAt.UTC().Format("20060102T150405Z"))
+}
+
+// readBackupFile loads a backup file previously written by backupWriter.
+func readBackupFile(path string) (BackupFile, error) {
+	var file BackupFile
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return file, err
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return file, err
+	}
+	return file, nil
+}
+
+// runRestore implements the `restore` subcommand: it reads a backup file and
+// re-creates every record it contains via the provider's CreateRecord.
+func runRestore(cmd *cobra.Command, args []string) error {
+	file, err := readBackupFile(restoreBackupFile)
+	if err != nil {
+		return fmt.Errorf("failed to read backup file %s: %w", restoreBackupFile, err)
+	}
+
+	providerName := provider
+	if providerName == "" {
+		providerName = file.Provider
+	}
+	api, err := newDNSProvider(providerName)
+	if err != nil {
+		return fmt.Errorf("failed to create DNS provider %s: %w", providerName, err)
+	}
+
+	var restored, failed int
+	for _, rec := range file.Records {
+		record := DNSRecord{
+			ZoneID:   rec.ZoneID,
+			Name:     rec.Hostname,
+			Type:     rec.Type,
+			Content:  rec.Content,
+			TTL:      rec.TTL,
+			Priority: rec.Priority,
+			Proxied:  rec.Proxied,
+		}
+		if err := api.CreateRecord(context.Background(), rec.ZoneID, record); err != nil {
+			logger.Error("Failed to restore record", zap.String("hostname", rec.Hostname), zap.String("type", rec.Type), zap.Error(err))
+			failed++
+			continue
+		}
+		logger.Info("Restored record", zap.String("hostname", rec.Hostname), zap.String("type", rec.Type), zap.String("content", rec.Content))
+		restored++
+	}
+
+	logger.Info("Restore summary", zap.Int("restored", restored), zap.Int("failed", failed))
+	return nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// recordFilter narrows which records fetched for a hostname are actually
+// eligible for deletion. A zero-value recordFilter matches everything.
+type recordFilter struct {
+	Types     map[string]bool // record types to allow; nil/empty means any type
+	ContentRE *regexp.Regexp  // content must match this regex if set
+	Proxied   *bool           // proxied status must match if set; nil means any
+}
+
+// matches reports whether record satisfies every predicate in f.
+func (f recordFilter) matches(record DNSRecord) bool {
+	if len(f.Types) > 0 && !f.Types[strings.ToUpper(record.Type)] {
+		return false
+	}
+	if f.ContentRE != nil && !f.ContentRE.MatchString(record.Content) {
+		return false
+	}
+	if f.Proxied != nil && *f.Proxied != record.Proxied {
+		return false
+	}
+	return true
+}
+
+// typeHint returns the single record type f restricts to, for providers that
+// can narrow their upstream query by type, or "" if f allows more than one
+// type (or any type).
+func (f recordFilter) typeHint() string {
+	if len(f.Types) != 1 {
+		return ""
+	}
+	for t := range f.Types {
+		return t
+	}
+	return ""
+}
+
+// parseTypeList turns a comma-separated list like "A,AAAA,CNAME" into a set
+// of upper-cased record types. An empty string yields a nil (unrestricted) set.
+func parseTypeList(s string) map[string]bool {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	types := make(map[string]bool)
+	for _, t := range strings.Split(s, ",") {
+		t = strings.ToUpper(strings.TrimSpace(t))
+		if t != "" {
+			types[t] = true
+		}
+	}
+	return types
+}
+
+// parseProxiedFlag parses the --proxied flag value: "true", "false", or
+// "any" (the default, meaning no restriction).
+func parseProxiedFlag(s string) (*bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "any":
+		return nil, nil
+	case "true":
+		v := true
+		return &v, nil
+	case "false":
+		v := false
+		return &v, nil
+	default:
+		return nil, fmt.Errorf("invalid --proxied value %q: must be true, false, or any", s)
+	}
+}
+
+// applyInlineFilters parses the "key=value" tokens that may trail a hostname
+// in the input file (e.g. "type=CNAME proxied=false") and overlays them onto
+// base, which is usually the filter built from the global CLI flags.
+func applyInlineFilters(tokens []string, base recordFilter) (recordFilter, error) {
+	filter := base
+	for _, tok := range tokens {
+		key, value, ok := strings.Cut(tok, "=")
+		if !ok {
+			return filter, fmt.Errorf("invalid inline filter %q: expected key=value", tok)
+		}
+		switch strings.ToLower(key) {
+		case "type":
+			filter.Types = parseTypeList(value)
+		case "content-regex":
+			re, err := regexp.Compile(value)
+			if err != nil {
+				return filter, fmt.Errorf("invalid content-regex %q: %w", value, err)
+			}
+			filter.ContentRE = re
+		case "proxied":
+			proxied, err := parseProxiedFlag(value)
+			if err != nil {
+				return filter, err
+			}
+			filter.Proxied = proxied
+		default:
+			return filter, fmt.Errorf("unknown inline filter key %q", key)
+		}
+	}
+	return filter, nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import "testing"
+
+func TestRecordFilter_Matches(t *testing.T) {
+	proxied := true
+	filter := recordFilter{
+		Types:   map[string]bool{"A": true},
+		Proxied: &proxied,
+	}
+
+	cases := []struct {
+		name   string
+		record DNSRecord
+		want   bool
+	}{
+		{"matches type and proxied", DNSRecord{Type: "A", Proxied: true}, true},
+		{"wrong type", DNSRecord{Type: "CNAME", Proxied: true}, false},
+		{"wrong proxied", DNSRecord{Type: "A", Proxied: false}, false},
+	}
+	for _, c := range cases {
+		if got := filter.matches(c.record); got != c.want {
+			t.Errorf("%s: matches = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestRecordFilter_Matches_ContentRegex(t *testing.T) {
+	filter, err := applyInlineFilters([]string{"content-regex=^203\\."}, recordFilter{})
+	if err != nil {
+		t.Fatalf("applyInlineFilters returned error: %v", err)
+	}
+
+	if !filter.matches(DNSRecord{Content: "203.0.113.1"}) {
+		t.Error("expected record matching content-regex to match")
+	}
+	if filter.matches(DNSRecord{Content: "198.51.100.1"}) {
+		t.Error("expected record not matching content-regex to not match")
+	}
+}
+
+func TestRecordFilter_Matches_ZeroValue(t *testing.T) {
+	var filter recordFilter
+	if !filter.matches(DNSRecord{Type: "TXT", Content: "anything", Proxied: true}) {
+		t.Error("zero-value recordFilter should match every record")
+	}
+}
+
+func TestRecordFilter_TypeHint(t *testing.T) {
+	cases := []struct {
+		name string
+		f    recordFilter
+		want string
+	}{
+		{"no types", recordFilter{}, ""},
+		{"one type", recordFilter{Types: map[string]bool{"CNAME": true}}, "CNAME"},
+		{"multiple types", recordFilter{Types: map[string]bool{"A": true, "AAAA": true}}, ""},
+	}
+	for _, c := range cases {
+		if got := c.f.typeHint(); got != c.want {
+			t.Errorf("%s: typeHint = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestApplyInlineFilters(t *testing.T) {
+	base := recordFilter{Types: map[string]bool{"A": true}}
+
+	filter, err := applyInlineFilters([]string{"type=CNAME,TXT", "proxied=false"}, base)
+	if err != nil {
+		t.Fatalf("applyInlineFilters returned error: %v", err)
+	}
+	if !filter.Types["CNAME"] || !filter.Types["TXT"] || len(filter.Types) != 2 {
+		t.Errorf("Types = %v, want {CNAME, TXT}", filter.Types)
+	}
+	if filter.Proxied == nil || *filter.Proxied != false {
+		t.Errorf("Proxied = %v, want false", filter.Proxied)
+	}
+}
+
+func TestApplyInlineFilters_InvalidToken(t *testing.T) {
+	if _, err := applyInlineFilters([]string{"not-a-key-value"}, recordFilter{}); err == nil {
+		t.Error("expected error for token without '=', got nil")
+	}
+}
+
+func TestApplyInlineFilters_UnknownKey(t *testing.T) {
+	if _, err := applyInlineFilters([]string{"bogus=1"}, recordFilter{}); err == nil {
+		t.Error("expected error for unknown filter key, got nil")
+	}
+}
+
+func TestApplyInlineFilters_InvalidProxiedValue(t *testing.T) {
+	if _, err := applyInlineFilters([]string{"proxied=maybe"}, recordFilter{}); err == nil {
+		t.Error("expected error for invalid proxied value, got nil")
+	}
+}
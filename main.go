@@ -5,18 +5,32 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 
-	"github.com/cloudflare/cloudflare-go"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"golang.org/x/time/rate"
 )
 
 var (
-	apply     bool   // Set to true for dry run, false to perform actual deletions
-	apiToken  string // Cloudflare API token
-	inputFile string // Filename for the list of DNS records to delete
+	apply         bool   // Set to true to perform actual deletions, false for a dry run
+	apiToken      string // Cloudflare API token
+	doAPIToken    string // DigitalOcean API token
+	inputFile     string // Filename for the list of DNS records to delete
+	provider      string // DNS backend to operate against
+	typeFilter    string // Comma-separated list of record types to restrict deletion to
+	contentRegex  string // Only delete records whose content matches this regex
+	proxiedFilter string // Only delete records with this proxied status: true, false, or any
+	concurrency   int    // Number of hostnames to process concurrently
+	rateLimit     float64
+	maxRetries    int
+	outputFormat  string // Result/plan output format: text, json, or csv
+	planFile      string // Path to the plan document written/read by plan and apply-plan
+	backupDir     string // Directory to write per-zone backups of deleted records to, before deleting
+
+	restoreBackupFile string // Path to the backup file restore reads records from
 
 	logger *zap.Logger
 
@@ -26,6 +40,24 @@ var (
 		Long:  "A CLI tool to remove DNS records from Cloudflare based on a list of hostnames",
 		Run:   run,
 	}
+
+	planCmd = &cobra.Command{
+		Use:   "plan",
+		Short: "Compute the DNS records that would be deleted and write them to a plan file",
+		RunE:  runPlan,
+	}
+
+	applyPlanCmd = &cobra.Command{
+		Use:   "apply-plan",
+		Short: "Execute exactly the deletions recorded in a plan file",
+		RunE:  runApplyPlan,
+	}
+
+	restoreCmd = &cobra.Command{
+		Use:   "restore",
+		Short: "Re-create records from a backup file written by a previous run",
+		RunE:  runRestore,
+	}
 )
 
 func init() {
@@ -36,6 +68,21 @@ func init() {
 	rootCmd.PersistentFlags().BoolVarP(&apply, "apply", "a", false, "Apply changes")
 	rootCmd.PersistentFlags().StringVarP(&apiToken, "apitoken", "t", "", "Cloudflare API token")
 	rootCmd.PersistentFlags().StringVarP(&inputFile, "filename", "f", "hostnames.txt", "Filename for the list of DNS records to delete")
+	rootCmd.PersistentFlags().StringVarP(&provider, "provider", "p", "cloudflare", "DNS backend to use (cloudflare, digitalocean)")
+	rootCmd.PersistentFlags().StringVar(&typeFilter, "type", "", "Comma-separated record types to restrict deletion to (e.g. A,AAAA,CNAME)")
+	rootCmd.PersistentFlags().StringVar(&contentRegex, "content-regex", "", "Only delete records whose content matches this regex")
+	rootCmd.PersistentFlags().StringVar(&proxiedFilter, "proxied", "any", "Only delete records with this proxied status: true, false, or any")
+	rootCmd.PersistentFlags().IntVar(&concurrency, "concurrency", 8, "Number of hostnames to process concurrently")
+	rootCmd.PersistentFlags().Float64Var(&rateLimit, "rate-limit", 4, "Maximum API requests per second")
+	rootCmd.PersistentFlags().IntVar(&maxRetries, "max-retries", 5, "Maximum retries for rate-limited API requests")
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "output", "text", "Result output format: text, json, or csv")
+	rootCmd.PersistentFlags().StringVar(&backupDir, "backup-dir", "", "If set, back up each deleted record to a JSON file under this directory before deleting it")
+
+	planCmd.Flags().StringVar(&planFile, "plan-file", "plan.json", "Path to write the plan document to")
+	applyPlanCmd.Flags().StringVar(&planFile, "plan-file", "plan.json", "Path to the plan document to execute")
+	restoreCmd.Flags().StringVar(&restoreBackupFile, "backup", "", "Path to the backup file to restore records from")
+	restoreCmd.MarkFlagRequired("backup")
+	rootCmd.AddCommand(planCmd, applyPlanCmd, restoreCmd)
 
 	// Mark apiToken as required flags
 	//rootCmd.MarkPersistentFlagRequired("apitoken")
@@ -44,7 +91,8 @@ func init() {
 func main() {
 	// Configuration via environment variables
 	apiToken = os.Getenv("CLOUDFLARE_API_TOKEN")
-	if apiToken == "" {
+	doAPIToken = os.Getenv("DIGITALOCEAN_API_TOKEN")
+	if apiToken == "" && doAPIToken == "" {
 		logger.Fatal("CLOUDFLARE_API_TOKEN environment variable is not set")
 	}
 
@@ -69,50 +117,159 @@ func main() {
 	}
 }
 
+// rateLimitBurst returns the burst size to pair with a rate.Limit of
+// rateLimit requests/sec. Truncating rateLimit itself would make the burst 0
+// for any --rate-limit below 1 (e.g. 0.5), which makes every limiter.Wait
+// call fail outright since a single request already exceeds a zero burst.
+func rateLimitBurst(rateLimit float64) int {
+	if burst := int(rateLimit); burst > 1 {
+		return burst
+	}
+	return 1
+}
+
+// buildDefaultFilter builds the recordFilter described by the global
+// --type/--content-regex/--proxied flags. Per-line inline filters in the
+// input file override individual fields of the result.
+func buildDefaultFilter() (recordFilter, error) {
+	proxied, err := parseProxiedFlag(proxiedFilter)
+	if err != nil {
+		return recordFilter{}, fmt.Errorf("invalid --proxied value: %w", err)
+	}
+	filter := recordFilter{Types: parseTypeList(typeFilter), Proxied: proxied}
+	if contentRegex != "" {
+		re, err := regexp.Compile(contentRegex)
+		if err != nil {
+			return recordFilter{}, fmt.Errorf("invalid --content-regex value: %w", err)
+		}
+		filter.ContentRE = re
+	}
+	return filter, nil
+}
+
 func run(cmd *cobra.Command, args []string) {
+	defaultFilter, err := buildDefaultFilter()
+	if err != nil {
+		logger.Fatal(err.Error())
+	}
+
 	// Read the list of hostnames from the input file
-	hostnames, err := readInputFile(inputFile)
+	entries, err := readInputFile(inputFile, defaultFilter)
 	if err != nil {
 		logger.Fatal("Failed to read file", zap.String("filename", inputFile), zap.Error(err))
 	}
 
-	// Create a new API instance
-	api, err := cloudflare.NewWithAPIToken(apiToken)
+	// Create the DNS provider selected via --provider
+	api, err := newDNSProvider(provider)
 	if err != nil {
-		logger.Fatal("Failed to create API instance", zap.Error(err))
+		logger.Fatal("Failed to create DNS provider", zap.String("provider", provider), zap.Error(err))
 	}
 
-	if len(hostnames) == 0 {
+	if len(entries) == 0 {
 		logger.Info("No readable hostnames found in the input file", zap.String("filename", inputFile))
 		return
 
 	}
 
-	// Iterate over the list of hostnames and delete each record
-	for _, hostname := range hostnames {
-		// Parse the zone name from the given hostname
-		zoneName, err := getZoneNameFromRecord(hostname)
-		if err != nil {
-			logger.Error("Failed to parse zone name from record", zap.String("zoneName", zoneName), zap.Error(err))
-		}
-		// Fetch the zone ID for the given zone name
-		zoneID, err := api.ZoneIDByName(zoneName)
-		if err != nil {
-			logger.Error("Failed to fetch zone ID", zap.String("zoneName", zoneName), zap.Error(err))
-		}
-		// Fetch the DNS records for the given hostname
-		records, err := fetchDNSRecords(api, zoneID, hostname)
-		if err != nil {
-			continue
+	var backup *backupWriter
+	if backupDir != "" {
+		backup = newBackupWriter(backupDir, provider)
+	}
+
+	// Process hostnames concurrently, throttled to rateLimit requests/sec
+	// and retried with backoff on rate-limit errors. Each deleted record is
+	// backed up to disk as it's deleted, not batched until the run ends.
+	limiter := rate.NewLimiter(rate.Limit(rateLimit), rateLimitBurst(rateLimit))
+	results := processHostnames(api, entries, concurrency, limiter, maxRetries, backup)
+
+	for _, result := range results {
+		if result.Status == statusFailed {
+			logger.Error("Failed to process hostname", zap.String("hostname", result.Hostname), zap.Error(result.Err))
 		}
-		// Delete the DNS records
-		for _, record := range records {
-			err = deleteDNSRecord(api, record)
-			if err != nil {
-				logger.Error("Error deleting record", zap.Error(err))
-			}
+	}
+
+	counts := summarize(results)
+	logger.Info("Run summary",
+		zap.Int("succeeded", counts[statusSucceeded]),
+		zap.Int("dry-run", counts[statusDryRun]),
+		zap.Int("failed", counts[statusFailed]),
+		zap.Int("skipped", counts[statusSkipped]),
+	)
+
+	if err := printResults(results, outputFormat); err != nil {
+		logger.Error("Failed to print results", zap.Error(err))
+	}
+}
+
+// runPlan implements the `plan` subcommand: it resolves zones and lists the
+// records that match the input file's hostnames/filters, without deleting
+// anything, and writes the result to --plan-file.
+func runPlan(cmd *cobra.Command, args []string) error {
+	defaultFilter, err := buildDefaultFilter()
+	if err != nil {
+		return err
+	}
+
+	entries, err := readInputFile(inputFile, defaultFilter)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s: %w", inputFile, err)
+	}
+
+	api, err := newDNSProvider(provider)
+	if err != nil {
+		return fmt.Errorf("failed to create DNS provider %s: %w", provider, err)
+	}
+
+	plan := buildPlan(api, entries, provider)
+
+	format := outputFormat
+	if format != "csv" {
+		format = "json"
+	}
+	if err := writePlanFile(planFile, format, plan); err != nil {
+		return fmt.Errorf("failed to write plan file %s: %w", planFile, err)
+	}
+
+	logger.Info("Wrote plan", zap.String("file", planFile), zap.Int("records", len(plan.Records)))
+	return nil
+}
+
+// runApplyPlan implements the `apply-plan` subcommand: it reads a plan file
+// written by `plan` and executes exactly those deletions, re-checking each
+// record's fingerprint first.
+func runApplyPlan(cmd *cobra.Command, args []string) error {
+	plan, err := readPlanFile(planFile)
+	if err != nil {
+		return fmt.Errorf("failed to read plan file %s: %w", planFile, err)
+	}
+
+	api, err := newDNSProvider(plan.Provider)
+	if err != nil {
+		return fmt.Errorf("failed to create DNS provider %s: %w", plan.Provider, err)
+	}
+
+	var backup *backupWriter
+	if backupDir != "" {
+		backup = newBackupWriter(backupDir, plan.Provider)
+	}
+
+	limiter := rate.NewLimiter(rate.Limit(rateLimit), rateLimitBurst(rateLimit))
+	results := applyPlan(api, plan, limiter, maxRetries, backup)
+
+	for _, result := range results {
+		if result.Status == statusFailed {
+			logger.Error("Failed to apply planned deletion", zap.String("id", result.Record.ID), zap.String("hostname", result.Record.Hostname), zap.Error(result.Err))
 		}
 	}
+
+	counts := summarizePlanResults(results)
+	logger.Info("Apply-plan summary",
+		zap.Int("succeeded", counts[statusSucceeded]),
+		zap.Int("dry-run", counts[statusDryRun]),
+		zap.Int("failed", counts[statusFailed]),
+		zap.Int("skipped", counts[statusSkipped]),
+	)
+	return nil
 }
 
 func createLogger() *zap.Logger {
@@ -144,56 +301,78 @@ func createLogger() *zap.Logger {
 	return zap.New(core)
 }
 
-// getZoneNameFromRecord parses the zone name from a given hostname
-func getZoneNameFromRecord(hostname string) (string, error) {
-	// Split the hostname into parts.
-	parts := strings.Split(hostname, ".")
-	if len(parts) < 2 {
-		return "", fmt.Errorf("invalid hostname: %s", hostname)
-	}
-
-	tld := strings.Join(parts[len(parts)-2:], ".")
-	return tld, nil
-}
-
 // Function to fetch DNS records for a given hostname
-func fetchDNSRecords(api *cloudflare.API, zoneID, hostname string) ([]cloudflare.DNSRecord, error) {
+func fetchDNSRecords(api DNSProvider, zoneID, hostname string, filter recordFilter) ([]DNSRecord, error) {
 	logger.Info("Fetching Record: ", zap.String("hostname", hostname))
-	records, _, err := api.ListDNSRecords(context.Background(), cloudflare.ZoneIdentifier(zoneID), cloudflare.ListDNSRecordsParams{Name: hostname})
+	records, err := api.ListRecords(context.Background(), zoneID, hostname, filter.typeHint())
 	if err != nil {
 		logger.Error("Failed to fetch DNS record", zap.String("hostname", hostname), zap.Error(err))
 		return nil, err
 	}
-	if len(records) == 0 {
+
+	filtered := records[:0]
+	for _, record := range records {
+		if filter.matches(record) {
+			filtered = append(filtered, record)
+		} else {
+			logger.Info("Skipping record that does not match filters", zap.String("hostname", hostname), zap.String("type", record.Type), zap.String("content", record.Content))
+		}
+	}
+
+	if len(filtered) == 0 {
 		logger.Info("No records found for", zap.String("hostname", hostname))
 	}
-	return records, nil
+	return filtered, nil
 }
 
 // Function to delete a DNS record
-func deleteDNSRecord(api *cloudflare.API, record cloudflare.DNSRecord) error {
+func deleteDNSRecord(api DNSProvider, zoneName string, record DNSRecord, backup *backupWriter) error {
 	if !apply {
-		logger.Info("[DRY RUN] Deleting Record: ", zap.String("recordID", record.ID), zap.String("zoneID", record.ZoneID), zap.String("name", record.Name), zap.String("type", record.Type), zap.String("content", record.Content), zap.Bool("proxied", *record.Proxied))
+		logger.Info("[DRY RUN] Deleting Record: ", zap.String("recordID", record.ID), zap.String("zoneID", record.ZoneID), zap.String("name", record.Name), zap.String("type", record.Type), zap.String("content", record.Content), zap.Bool("proxied", record.Proxied))
 		return nil
 	}
-	err := api.DeleteDNSRecord(context.Background(), cloudflare.ZoneIdentifier(record.ZoneID), record.ID)
+	if backup != nil {
+		if err := backup.add(zoneName, BackupRecord{
+			ZoneID:   record.ZoneID,
+			Hostname: record.Name,
+			Type:     record.Type,
+			Content:  record.Content,
+			TTL:      record.TTL,
+			Priority: record.Priority,
+			Proxied:  record.Proxied,
+		}); err != nil {
+			logger.Error("Failed to write backup before deleting record", zap.String("recordID", record.ID), zap.Error(err))
+			return err
+		}
+	}
+	err := api.DeleteRecord(context.Background(), record.ZoneID, record)
 	if err != nil {
 		logger.Error("Failed to delete DNS record", zap.String("recordID", record.ID), zap.String("name", record.Name), zap.Error(err))
 		return err
 	}
-	logger.Warn("Deleted Record: ", zap.String("recordID", record.ID), zap.String("zoneID", record.ZoneID), zap.String("name", record.Name), zap.String("type", record.Type), zap.String("content", record.Content), zap.Bool("proxied", *record.Proxied))
+	logger.Warn("Deleted Record: ", zap.String("recordID", record.ID), zap.String("zoneID", record.ZoneID), zap.String("name", record.Name), zap.String("type", record.Type), zap.String("content", record.Content), zap.Bool("proxied", record.Proxied))
 	return nil
 }
 
+// hostnameEntry is one line of the input file: the hostname to clean up and
+// the filter that determines which of its records are eligible for deletion.
+type hostnameEntry struct {
+	Hostname string
+	Filter   recordFilter
+}
+
 // readInputFile reads the input file, skipping empty lines and comments.
-func readInputFile(filePath string) ([]string, error) {
+// Each line is a hostname optionally followed by space-separated inline
+// filters (e.g. "foo.example.com type=CNAME proxied=false") that override the
+// corresponding field of defaultFilter for that hostname only.
+func readInputFile(filePath string, defaultFilter recordFilter) ([]hostnameEntry, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
 
-	var hostnames []string
+	var entries []hostnameEntry
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		// trim leading and trailing spaces
@@ -202,12 +381,20 @@ func readInputFile(filePath string) ([]string, error) {
 		if line == "" || strings.HasPrefix(line, "#") {
 			continue
 		}
-		hostnames = append(hostnames, line)
+
+		fields := strings.Fields(line)
+		hostname := fields[0]
+		filter, err := applyInlineFilters(fields[1:], defaultFilter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filters for hostname %s: %w", hostname, err)
+		}
+
+		entries = append(entries, hostnameEntry{Hostname: hostname, Filter: filter})
 	}
 
 	if err := scanner.Err(); err != nil {
 		return nil, err
 	}
 
-	return hostnames, nil
+	return entries, nil
 }
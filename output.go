@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// resultRecord is the JSON/CSV-serializable view of a hostnameResult.
+type resultRecord struct {
+	Hostname string `json:"hostname"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+}
+
+func toResultRecords(results []hostnameResult) []resultRecord {
+	records := make([]resultRecord, 0, len(results))
+	for _, r := range results {
+		rec := resultRecord{Hostname: r.Hostname, Status: string(r.Status)}
+		if r.Err != nil {
+			rec.Error = r.Err.Error()
+		}
+		records = append(records, rec)
+	}
+	return records
+}
+
+// printResults writes the per-hostname outcome of a run to stdout in the
+// requested format: text, json, or csv.
+func printResults(results []hostnameResult, format string) error {
+	records := toResultRecords(results)
+
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		if err := w.Write([]string{"hostname", "status", "error"}); err != nil {
+			return err
+		}
+		for _, rec := range records {
+			if err := w.Write([]string{rec.Hostname, rec.Status, rec.Error}); err != nil {
+				return err
+			}
+		}
+		w.Flush()
+		return w.Error()
+	default:
+		for _, rec := range records {
+			if rec.Error != "" {
+				fmt.Printf("%s: %s (%s)\n", rec.Hostname, rec.Status, rec.Error)
+			} else {
+				fmt.Printf("%s: %s\n", rec.Hostname, rec.Status)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+// PlanRecord is a single DNS record slated for deletion, as captured by the
+// `plan` subcommand.
+type PlanRecord struct {
+	ID       string `json:"id"`
+	ZoneID   string `json:"zone_id"`
+	ZoneName string `json:"zone_name"`
+	Hostname string `json:"hostname"`
+	Type     string `json:"type"`
+	Content  string `json:"content"`
+	TTL      int    `json:"ttl"`
+	Priority uint16 `json:"priority"`
+	Proxied  bool   `json:"proxied"`
+}
+
+// fingerprint is the etag-like signature `apply-plan` re-checks before
+// deleting, so a record that changed after the plan was generated is
+// skipped rather than blindly removed.
+func (p PlanRecord) fingerprint() string {
+	return p.Type + "|" + p.Content
+}
+
+// Plan is the structured document produced by `plan` and consumed by
+// `apply-plan`.
+type Plan struct {
+	Provider    string       `json:"provider"`
+	GeneratedAt time.Time    `json:"generated_at"`
+	Records     []PlanRecord `json:"records"`
+}
+
+// buildPlan resolves zones and lists the records matching each entry,
+// without deleting anything.
+func buildPlan(api DNSProvider, entries []hostnameEntry, providerName string) Plan {
+	cache := newZoneCache()
+	plan := Plan{Provider: providerName, GeneratedAt: time.Now()}
+
+	for _, entry := range entries {
+		zoneName, zoneID, err := resolveZoneID(api, entry.Hostname, cache)
+		if err != nil {
+			logger.Error("Failed to resolve zone for hostname", zap.String("hostname", entry.Hostname), zap.Error(err))
+			continue
+		}
+
+		records, err := fetchDNSRecords(api, zoneID, entry.Hostname, entry.Filter)
+		if err != nil {
+			continue
+		}
+
+		for _, record := range records {
+			plan.Records = append(plan.Records, PlanRecord{
+				ID:       record.ID,
+				ZoneID:   zoneID,
+				ZoneName: zoneName,
+				Hostname: entry.Hostname,
+				Type:     record.Type,
+				Content:  record.Content,
+				TTL:      record.TTL,
+				Priority: record.Priority,
+				Proxied:  record.Proxied,
+			})
+		}
+	}
+
+	return plan
+}
+
+var planCSVHeader = []string{"id", "zone_id", "zone_name", "hostname", "type", "content", "ttl", "priority", "proxied"}
+
+// writePlanFile serializes plan to path as "json" or "csv".
+func writePlanFile(path, format string, plan Plan) error {
+	if format == "csv" {
+		return writePlanCSV(path, plan)
+	}
+	return writePlanJSON(path, plan)
+}
+
+func writePlanJSON(path string, plan Plan) error {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func writePlanCSV(path string, plan Plan) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := csv.NewWriter(file)
+	if err := w.Write(planCSVHeader); err != nil {
+		return err
+	}
+	for _, r := range plan.Records {
+		row := []string{r.ID, r.ZoneID, r.ZoneName, r.Hostname, r.Type, r.Content, strconv.Itoa(r.TTL), strconv.Itoa(int(r.Priority)), strconv.FormatBool(r.Proxied)}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// readPlanFile loads a plan previously written by writePlanFile. The format
+// is inferred from the file extension; anything other than ".csv" is
+// treated as JSON.
+func readPlanFile(path string) (Plan, error) {
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return readPlanCSV(path)
+	}
+	return readPlanJSON(path)
+}
+
+func readPlanJSON(path string) (Plan, error) {
+	var plan Plan
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return plan, err
+	}
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return plan, err
+	}
+	return plan, nil
+}
+
+func readPlanCSV(path string) (Plan, error) {
+	var plan Plan
+	file, err := os.Open(path)
+	if err != nil {
+		return plan, err
+	}
+	defer file.Close()
+
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return plan, err
+	}
+	if len(rows) == 0 {
+		return plan, fmt.Errorf("empty plan file: %s", path)
+	}
+
+	for _, row := range rows[1:] {
+		if len(row) != len(planCSVHeader) {
+			return plan, fmt.Errorf("malformed plan row: %v", row)
+		}
+		ttl, err := strconv.Atoi(row[6])
+		if err != nil {
+			return plan, fmt.Errorf("invalid ttl in plan row: %w", err)
+		}
+		priority, err := strconv.ParseUint(row[7], 10, 16)
+		if err != nil {
+			return plan, fmt.Errorf("invalid priority in plan row: %w", err)
+		}
+		proxied, err := strconv.ParseBool(row[8])
+		if err != nil {
+			return plan, fmt.Errorf("invalid proxied value in plan row: %w", err)
+		}
+		plan.Records = append(plan.Records, PlanRecord{
+			ID: row[0], ZoneID: row[1], ZoneName: row[2], Hostname: row[3],
+			Type: row[4], Content: row[5], TTL: ttl, Priority: uint16(priority), Proxied: proxied,
+		})
+	}
+	return plan, nil
+}
+
+// planResult is the outcome of applying one PlanRecord.
+type planResult struct {
+	Record PlanRecord
+	Status hostnameStatus
+	Err    error
+}
+
+// applyPlan executes exactly the deletions recorded in plan, verifying each
+// record's fingerprint still matches before deleting it.
+func applyPlan(api DNSProvider, plan Plan, limiter *rate.Limiter, maxRetries int, backup *backupWriter) []planResult {
+	results := make([]planResult, 0, len(plan.Records))
+	for _, pr := range plan.Records {
+		status, err := applyPlanRecord(api, pr, limiter, maxRetries, backup)
+		results = append(results, planResult{Record: pr, Status: status, Err: err})
+	}
+	return results
+}
+
+func applyPlanRecord(api DNSProvider, pr PlanRecord, limiter *rate.Limiter, maxRetries int, backup *backupWriter) (hostnameStatus, error) {
+	ctx := context.Background()
+
+	var current []DNSRecord
+	err := withRetry(ctx, limiter, maxRetries, func() error {
+		var fetchErr error
+		current, fetchErr = fetchDNSRecords(api, pr.ZoneID, pr.Hostname, recordFilter{})
+		return fetchErr
+	})
+	if err != nil {
+		return statusFailed, err
+	}
+
+	var match *DNSRecord
+	for i := range current {
+		if current[i].ID == pr.ID {
+			match = &current[i]
+			break
+		}
+	}
+	if match == nil {
+		logger.Warn("Planned record no longer exists, skipping", zap.String("id", pr.ID), zap.String("hostname", pr.Hostname))
+		return statusSkipped, nil
+	}
+	if match.Type+"|"+match.Content != pr.fingerprint() {
+		logger.Warn("Planned record changed since plan was generated, skipping", zap.String("id", pr.ID), zap.String("hostname", pr.Hostname))
+		return statusSkipped, nil
+	}
+
+	record := DNSRecord{ID: pr.ID, ZoneID: pr.ZoneID, Name: pr.Hostname, Type: pr.Type, Content: pr.Content, TTL: pr.TTL, Priority: pr.Priority, Proxied: pr.Proxied}
+	if err := withRetry(ctx, limiter, maxRetries, func() error {
+		return deleteDNSRecord(api, pr.ZoneName, record, backup)
+	}); err != nil {
+		return statusFailed, err
+	}
+	if !apply {
+		return statusDryRun, nil
+	}
+	return statusSucceeded, nil
+}
+
+// summarizePlanResults tallies planResults by status for the apply-plan summary.
+func summarizePlanResults(results []planResult) map[hostnameStatus]int {
+	counts := make(map[hostnameStatus]int)
+	for _, r := range results {
+		counts[r.Status]++
+	}
+	return counts
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func testPlan() Plan {
+	return Plan{
+		Provider: "cloudflare",
+		Records: []PlanRecord{
+			{
+				ID:       "rec-1",
+				ZoneID:   "zone-1",
+				ZoneName: "example.com",
+				Hostname: "foo.example.com",
+				Type:     "A",
+				Content:  "203.0.113.1",
+				TTL:      300,
+				Priority: 10,
+				Proxied:  true,
+			},
+			{
+				ID:       "rec-2",
+				ZoneID:   "zone-1",
+				ZoneName: "example.com",
+				Hostname: "bar.example.com",
+				Type:     "MX",
+				Content:  "mail.example.com",
+				TTL:      3600,
+				Priority: 0,
+				Proxied:  false,
+			},
+		},
+	}
+}
+
+func TestWriteReadPlanFile_JSON(t *testing.T) {
+	want := testPlan()
+	path := filepath.Join(t.TempDir(), "plan.json")
+
+	if err := writePlanFile(path, "json", want); err != nil {
+		t.Fatalf("writePlanFile returned error: %v", err)
+	}
+
+	got, err := readPlanFile(path)
+	if err != nil {
+		t.Fatalf("readPlanFile returned error: %v", err)
+	}
+	if got.Provider != want.Provider {
+		t.Errorf("Provider = %q, want %q", got.Provider, want.Provider)
+	}
+	if !reflect.DeepEqual(got.Records, want.Records) {
+		t.Errorf("Records = %+v, want %+v", got.Records, want.Records)
+	}
+}
+
+func TestWriteReadPlanFile_CSV(t *testing.T) {
+	want := testPlan()
+	path := filepath.Join(t.TempDir(), "plan.csv")
+
+	if err := writePlanFile(path, "csv", want); err != nil {
+		t.Fatalf("writePlanFile returned error: %v", err)
+	}
+
+	got, err := readPlanFile(path)
+	if err != nil {
+		t.Fatalf("readPlanFile returned error: %v", err)
+	}
+	if !reflect.DeepEqual(got.Records, want.Records) {
+		t.Errorf("Records = %+v, want %+v", got.Records, want.Records)
+	}
+}
+
+func TestReadPlanCSV_MalformedRow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.csv")
+	content := "id,zone_id,zone_name,hostname,type,content,ttl,priority,proxied\nrec-1,zone-1,example.com,foo.example.com,A\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write test fixture: %v", err)
+	}
+
+	if _, err := readPlanFile(path); err == nil {
+		t.Error("expected error for malformed row, got nil")
+	}
+}
+
+func TestPlanRecord_Fingerprint(t *testing.T) {
+	a := PlanRecord{Type: "A", Content: "203.0.113.1"}
+	b := PlanRecord{Type: "A", Content: "203.0.113.1", TTL: 600}
+	c := PlanRecord{Type: "A", Content: "203.0.113.2"}
+
+	if a.fingerprint() != b.fingerprint() {
+		t.Error("fingerprint should ignore fields other than type and content")
+	}
+	if a.fingerprint() == c.fingerprint() {
+		t.Error("fingerprint should change when content changes")
+	}
+}
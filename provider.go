@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// DNSRecord is the provider-agnostic representation of a DNS record that the
+// rest of the tool operates on. Provider adapters are responsible for
+// converting their native record types to and from this shape.
+type DNSRecord struct {
+	ID       string
+	ZoneID   string
+	Name     string
+	Type     string
+	Content  string
+	TTL      int
+	Priority uint16
+	Proxied  bool
+}
+
+// DNSProvider is the interface that every supported DNS backend must
+// implement. It covers what `run` needs to resolve a zone, list and delete
+// records, plus CreateRecord so `restore` can re-create records from a
+// backup.
+type DNSProvider interface {
+	// ZoneIDByName resolves a zone name (e.g. "example.com") to whatever
+	// identifier the provider needs to address that zone in later calls.
+	// For providers without a separate zone ID concept, the zone name
+	// itself may be returned.
+	ZoneIDByName(zoneName string) (string, error)
+
+	// ListRecords returns the records matching hostname within the zone
+	// identified by zoneID. typeHint, if non-empty, is a single record type
+	// (e.g. "A") the caller is restricting to; providers that can narrow
+	// their upstream query by type should do so, but must not assume the
+	// result is otherwise pre-filtered.
+	ListRecords(ctx context.Context, zoneID, hostname, typeHint string) ([]DNSRecord, error)
+
+	// DeleteRecord removes the given record from the zone identified by
+	// zoneID.
+	DeleteRecord(ctx context.Context, zoneID string, record DNSRecord) error
+
+	// CreateRecord creates a new record in the zone identified by zoneID,
+	// used by `restore` to re-create records from a backup.
+	CreateRecord(ctx context.Context, zoneID string, record DNSRecord) error
+}
+
+// newDNSProvider constructs the DNSProvider selected by --provider, reading
+// whatever credentials that provider needs from the environment.
+func newDNSProvider(name string) (DNSProvider, error) {
+	switch name {
+	case "cloudflare", "":
+		return newCloudflareProvider(apiToken)
+	case "digitalocean":
+		return newDigitalOceanProvider(doAPIToken)
+	default:
+		return nil, fmt.Errorf("unsupported provider: %s", name)
+	}
+}
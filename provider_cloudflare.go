@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+
+	"github.com/cloudflare/cloudflare-go"
+)
+
+// cloudflareProvider adapts the cloudflare-go client to the DNSProvider
+// interface.
+type cloudflareProvider struct {
+	api *cloudflare.API
+}
+
+func newCloudflareProvider(apiToken string) (DNSProvider, error) {
+	api, err := cloudflare.NewWithAPIToken(apiToken)
+	if err != nil {
+		return nil, err
+	}
+	return &cloudflareProvider{api: api}, nil
+}
+
+func (p *cloudflareProvider) ZoneIDByName(zoneName string) (string, error) {
+	return p.api.ZoneIDByName(zoneName)
+}
+
+func (p *cloudflareProvider) ListRecords(ctx context.Context, zoneID, hostname, typeHint string) ([]DNSRecord, error) {
+	records, _, err := p.api.ListDNSRecords(ctx, cloudflare.ZoneIdentifier(zoneID), cloudflare.ListDNSRecordsParams{Name: hostname, Type: typeHint})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]DNSRecord, 0, len(records))
+	for _, r := range records {
+		proxied := false
+		if r.Proxied != nil {
+			proxied = *r.Proxied
+		}
+		priority := uint16(0)
+		if r.Priority != nil {
+			priority = *r.Priority
+		}
+		out = append(out, DNSRecord{
+			ID:       r.ID,
+			ZoneID:   zoneID,
+			Name:     r.Name,
+			Type:     r.Type,
+			Content:  r.Content,
+			TTL:      r.TTL,
+			Priority: priority,
+			Proxied:  proxied,
+		})
+	}
+	return out, nil
+}
+
+func (p *cloudflareProvider) DeleteRecord(ctx context.Context, zoneID string, record DNSRecord) error {
+	return p.api.DeleteDNSRecord(ctx, cloudflare.ZoneIdentifier(zoneID), record.ID)
+}
+
+func (p *cloudflareProvider) CreateRecord(ctx context.Context, zoneID string, record DNSRecord) error {
+	priority := record.Priority
+	_, err := p.api.CreateDNSRecord(ctx, cloudflare.ZoneIdentifier(zoneID), cloudflare.CreateDNSRecordParams{
+		Type:     record.Type,
+		Name:     record.Name,
+		Content:  record.Content,
+		TTL:      record.TTL,
+		Priority: &priority,
+		Proxied:  &record.Proxied,
+	})
+	return err
+}
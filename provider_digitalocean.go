@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/libdns/digitalocean"
+	"github.com/libdns/libdns"
+)
+
+// digitalOceanProvider adapts the libdns DigitalOcean provider to the
+// DNSProvider interface. libdns addresses zones by name rather than by a
+// separate ID, so ZoneIDByName is a pass-through and zoneID is always a zone
+// name such as "example.com", without a trailing dot.
+type digitalOceanProvider struct {
+	client *digitalocean.Provider
+}
+
+func newDigitalOceanProvider(apiToken string) (DNSProvider, error) {
+	if apiToken == "" {
+		return nil, fmt.Errorf("DIGITALOCEAN_API_TOKEN environment variable is not set")
+	}
+	return &digitalOceanProvider{client: &digitalocean.Provider{APIToken: apiToken}}, nil
+}
+
+func (p *digitalOceanProvider) ZoneIDByName(zoneName string) (string, error) {
+	return libdns.AbsoluteName(zoneName, ""), nil
+}
+
+func (p *digitalOceanProvider) ListRecords(ctx context.Context, zoneID, hostname, typeHint string) ([]DNSRecord, error) {
+	records, err := p.client.GetRecords(ctx, zoneID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]DNSRecord, 0, len(records))
+	for _, rec := range records {
+		rr := rec.RR()
+		if libdns.AbsoluteName(rr.Name, zoneID) != hostname {
+			continue
+		}
+		var id string
+		if dns, ok := rec.(digitalocean.DNS); ok {
+			id = dns.ID
+		}
+		out = append(out, DNSRecord{
+			ID:      id,
+			ZoneID:  zoneID,
+			Name:    hostname,
+			Type:    rr.Type,
+			Content: rr.Data,
+			TTL:     int(rr.TTL / time.Second),
+			// libdns/digitalocean's godo conversion doesn't carry MX/SRV
+			// priority through, so Priority is left unset on this provider.
+		})
+	}
+	return out, nil
+}
+
+func (p *digitalOceanProvider) DeleteRecord(ctx context.Context, zoneID string, record DNSRecord) error {
+	_, err := p.client.DeleteRecords(ctx, zoneID, []libdns.Record{
+		digitalocean.DNS{
+			ID: record.ID,
+			Record: libdns.RR{
+				Name: libdns.RelativeName(record.Name, zoneID),
+				Type: record.Type,
+				Data: record.Content,
+				TTL:  time.Duration(record.TTL) * time.Second,
+			},
+		},
+	})
+	return err
+}
+
+func (p *digitalOceanProvider) CreateRecord(ctx context.Context, zoneID string, record DNSRecord) error {
+	_, err := p.client.AppendRecords(ctx, zoneID, []libdns.Record{
+		libdns.RR{
+			Name: libdns.RelativeName(record.Name, zoneID),
+			Type: record.Type,
+			Data: record.Content,
+			TTL:  time.Duration(record.TTL) * time.Second,
+		},
+	})
+	return err
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudflare/cloudflare-go"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+// hostnameStatus categorizes the outcome of processing one hostname.
+type hostnameStatus string
+
+const (
+	statusSucceeded hostnameStatus = "succeeded"
+	statusDryRun    hostnameStatus = "dry-run"
+	statusFailed    hostnameStatus = "failed"
+	statusSkipped   hostnameStatus = "skipped"
+)
+
+// hostnameResult is the outcome of processing a single hostnameEntry.
+type hostnameResult struct {
+	Hostname string
+	Status   hostnameStatus
+	Err      error
+}
+
+// processHostnames fans out entries across a pool of concurrency workers,
+// resolving zones, fetching, and deleting records. Record fetch and delete
+// calls made by workers are throttled by limiter and retried with backoff on
+// rate-limit errors; zone lookups are neither throttled nor retried.
+func processHostnames(api DNSProvider, entries []hostnameEntry, concurrency int, limiter *rate.Limiter, maxRetries int, backup *backupWriter) []hostnameResult {
+	cache := newZoneCache()
+
+	jobs := make(chan hostnameEntry)
+	resultsCh := make(chan hostnameResult)
+
+	var workers sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for entry := range jobs {
+				resultsCh <- processHostname(api, entry, cache, limiter, maxRetries, backup)
+			}
+		}()
+	}
+
+	go func() {
+		for _, entry := range entries {
+			jobs <- entry
+		}
+		close(jobs)
+	}()
+
+	go func() {
+		workers.Wait()
+		close(resultsCh)
+	}()
+
+	results := make([]hostnameResult, 0, len(entries))
+	for result := range resultsCh {
+		results = append(results, result)
+	}
+	return results
+}
+
+// processHostname resolves the zone for entry.Hostname and deletes every
+// record that matches entry.Filter.
+func processHostname(api DNSProvider, entry hostnameEntry, cache *zoneCache, limiter *rate.Limiter, maxRetries int, backup *backupWriter) hostnameResult {
+	hostname := entry.Hostname
+	ctx := context.Background()
+
+	zoneName, zoneID, err := resolveZoneID(api, hostname, cache)
+	if err != nil {
+		logger.Error("Failed to resolve zone for hostname", zap.String("hostname", hostname), zap.Error(err))
+		return hostnameResult{Hostname: hostname, Status: statusFailed, Err: err}
+	}
+	logger.Info("Resolved zone", zap.String("hostname", hostname), zap.String("zoneName", zoneName))
+
+	var records []DNSRecord
+	err = withRetry(ctx, limiter, maxRetries, func() error {
+		var fetchErr error
+		records, fetchErr = fetchDNSRecords(api, zoneID, hostname, entry.Filter)
+		return fetchErr
+	})
+	if err != nil {
+		return hostnameResult{Hostname: hostname, Status: statusFailed, Err: err}
+	}
+	if len(records) == 0 {
+		return hostnameResult{Hostname: hostname, Status: statusSkipped}
+	}
+
+	status := statusSucceeded
+	if !apply {
+		status = statusDryRun
+	}
+	for _, record := range records {
+		record := record
+		if err := withRetry(ctx, limiter, maxRetries, func() error {
+			return deleteDNSRecord(api, zoneName, record, backup)
+		}); err != nil {
+			status = statusFailed
+		}
+	}
+	return hostnameResult{Hostname: hostname, Status: status}
+}
+
+// withRetry calls fn, honoring limiter before every attempt and retrying
+// with exponential backoff plus jitter when fn fails with a rate-limit
+// error, up to maxRetries additional attempts.
+func withRetry(ctx context.Context, limiter *rate.Limiter, maxRetries int, fn func() error) error {
+	backoff := time.Second
+	var err error
+	for attempt := 0; ; attempt++ {
+		if limiter != nil {
+			if waitErr := limiter.Wait(ctx); waitErr != nil {
+				return waitErr
+			}
+		}
+
+		err = fn()
+		if err == nil || !isRateLimitErr(err) || attempt >= maxRetries {
+			return err
+		}
+
+		sleep := backoff + time.Duration(rand.Int63n(int64(backoff)))
+		logger.Warn("Rate limited, backing off", zap.Int("attempt", attempt+1), zap.Duration("sleep", sleep), zap.Error(err))
+		time.Sleep(sleep)
+		backoff *= 2
+	}
+}
+
+// isRateLimitErr reports whether err looks like a provider rate-limit
+// response. For Cloudflare, this checks the actual *cloudflare.RatelimitError
+// type rather than err's formatted message; other providers don't have a
+// typed rate-limit error yet, so those fall back to matching common wording.
+func isRateLimitErr(err error) bool {
+	if err == nil {
+		return false
+	}
+	var rlErr *cloudflare.RatelimitError
+	if errors.As(err, &rlErr) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "429") || strings.Contains(msg, "rate limit")
+}
+
+// summarize tallies results by status for the end-of-run report.
+func summarize(results []hostnameResult) map[hostnameStatus]int {
+	counts := make(map[hostnameStatus]int)
+	for _, r := range results {
+		counts[r.Status]++
+	}
+	return counts
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// zoneCache memoizes resolved zone IDs so that workers processing hostnames
+// in an already-resolved zone reuse its ID. Only successful lookups are
+// stored, and two workers that miss the cache at the same time both call
+// ZoneIDByName.
+type zoneCache struct {
+	mu    sync.Mutex
+	zones map[string]string
+}
+
+func newZoneCache() *zoneCache {
+	return &zoneCache{zones: make(map[string]string)}
+}
+
+func (c *zoneCache) get(zoneName string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id, ok := c.zones[zoneName]
+	return id, ok
+}
+
+func (c *zoneCache) set(zoneName, zoneID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.zones[zoneName] = zoneID
+}
+
+// getZoneNameFromRecord returns the registrable domain (eTLD+1) for hostname,
+// e.g. "foo.example.co.uk" -> "example.co.uk". Unlike a naive last-two-labels
+// join, this correctly handles multi-label public suffixes.
+func getZoneNameFromRecord(hostname string) (string, error) {
+	zoneName, err := publicsuffix.EffectiveTLDPlusOne(hostname)
+	if err != nil {
+		return "", fmt.Errorf("invalid hostname: %s: %w", hostname, err)
+	}
+	return zoneName, nil
+}
+
+// resolveZoneID finds the zone that should own hostname and returns both its
+// name and provider-specific ID. Most accounts only hold the registrable
+// domain as a zone, but some delegate a subdomain out as its own zone (e.g.
+// "internal.example.com"), so candidates are tried from most specific down to
+// the registrable domain, and the first one the provider recognizes wins.
+// Resolved zone IDs are cached in cache and reused for later hostnames in the
+// same zone; candidates that are not zones are not cached, so they are looked
+// up again for every hostname.
+func resolveZoneID(api DNSProvider, hostname string, cache *zoneCache) (zoneName, zoneID string, err error) {
+	registrable, err := getZoneNameFromRecord(hostname)
+	if err != nil {
+		return "", "", err
+	}
+
+	labels := strings.Split(hostname, ".")
+	for i := 0; i < len(labels); i++ {
+		candidate := strings.Join(labels[i:], ".")
+
+		if id, ok := cache.get(candidate); ok {
+			return candidate, id, nil
+		}
+
+		id, zoneErr := api.ZoneIDByName(candidate)
+		if zoneErr == nil && id != "" {
+			cache.set(candidate, id)
+			return candidate, id, nil
+		}
+
+		if candidate == registrable {
+			break
+		}
+	}
+
+	return "", "", fmt.Errorf("no zone found for hostname: %s", hostname)
+}
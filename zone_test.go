@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// fakeProvider is a minimal DNSProvider stub for exercising zone resolution.
+// zones maps zone names it "owns" to a zone ID; any other name fails lookup.
+type fakeProvider struct {
+	zones map[string]string
+}
+
+func (f *fakeProvider) ZoneIDByName(zoneName string) (string, error) {
+	if id, ok := f.zones[zoneName]; ok {
+		return id, nil
+	}
+	return "", fmt.Errorf("zone not found: %s", zoneName)
+}
+
+func (f *fakeProvider) ListRecords(ctx context.Context, zoneID, hostname, typeHint string) ([]DNSRecord, error) {
+	return nil, nil
+}
+
+func (f *fakeProvider) DeleteRecord(ctx context.Context, zoneID string, record DNSRecord) error {
+	return nil
+}
+
+func (f *fakeProvider) CreateRecord(ctx context.Context, zoneID string, record DNSRecord) error {
+	return nil
+}
+
+func TestGetZoneNameFromRecord_MultiLabelTLD(t *testing.T) {
+	cases := map[string]string{
+		"foo.example.co.uk":  "example.co.uk",
+		"bar.example.com.au": "example.com.au",
+		"www.example.com":    "example.com",
+		"example.com":        "example.com",
+	}
+
+	for hostname, want := range cases {
+		got, err := getZoneNameFromRecord(hostname)
+		if err != nil {
+			t.Fatalf("getZoneNameFromRecord(%q) returned error: %v", hostname, err)
+		}
+		if got != want {
+			t.Errorf("getZoneNameFromRecord(%q) = %q, want %q", hostname, got, want)
+		}
+	}
+}
+
+func TestResolveZoneID_RegistrableDomain(t *testing.T) {
+	api := &fakeProvider{zones: map[string]string{"example.com": "zone-root"}}
+	cache := newZoneCache()
+
+	zoneName, zoneID, err := resolveZoneID(api, "www.example.com", cache)
+	if err != nil {
+		t.Fatalf("resolveZoneID returned error: %v", err)
+	}
+	if zoneName != "example.com" || zoneID != "zone-root" {
+		t.Errorf("resolveZoneID = (%q, %q), want (\"example.com\", \"zone-root\")", zoneName, zoneID)
+	}
+	if id, _ := cache.get("example.com"); id != "zone-root" {
+		t.Errorf("expected resolved zone to be cached")
+	}
+}
+
+func TestResolveZoneID_DelegatedSubzone(t *testing.T) {
+	api := &fakeProvider{zones: map[string]string{
+		"example.com":          "zone-root",
+		"internal.example.com": "zone-internal",
+	}}
+	cache := newZoneCache()
+
+	zoneName, zoneID, err := resolveZoneID(api, "host.internal.example.com", cache)
+	if err != nil {
+		t.Fatalf("resolveZoneID returned error: %v", err)
+	}
+	if zoneName != "internal.example.com" || zoneID != "zone-internal" {
+		t.Errorf("resolveZoneID = (%q, %q), want the delegated subzone", zoneName, zoneID)
+	}
+}
+
+func TestResolveZoneID_NoZoneFound(t *testing.T) {
+	api := &fakeProvider{zones: map[string]string{}}
+	cache := newZoneCache()
+
+	if _, _, err := resolveZoneID(api, "www.example.com", cache); err == nil {
+		t.Error("expected error when no zone matches, got nil")
+	}
+}